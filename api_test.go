@@ -0,0 +1,159 @@
+package lcache
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestContainerStats(t *testing.T) {
+	wantErr := errors.New("boom")
+	c := Must(New(func(key string) (string, error) {
+		if key == "bad" {
+			return "", wantErr
+		}
+		return key, nil
+	}, time.Minute))
+
+	c.Get("a")   // miss + load
+	c.Get("a")   // hit
+	c.Get("bad") // miss + load error
+
+	s := c.Stats()
+	if s.Hits != 1 {
+		t.Errorf("Hits = %d, want 1", s.Hits)
+	}
+	if s.Misses != 2 {
+		t.Errorf("Misses = %d, want 2", s.Misses)
+	}
+	if s.Loads != 2 {
+		t.Errorf("Loads = %d, want 2", s.Loads)
+	}
+	if s.LoadErrors != 1 {
+		t.Errorf("LoadErrors = %d, want 1", s.LoadErrors)
+	}
+	if s.Size != 2 {
+		t.Errorf("Size = %d, want 2", s.Size)
+	}
+
+	c.Remove("a")
+	if got := c.Stats().Evictions; got != 1 {
+		t.Errorf("Evictions after Remove = %d, want 1", got)
+	}
+}
+
+// TestContainerStatsCountsLazyReloadAsLoad guards against Stats deciding
+// hit/miss purely from whether policy.Get found an entry: an entry can be
+// present but TTL-expired, in which case Get still synchronously reloads it
+// via fn, and that reload's outcome must show up in Loads/LoadErrors rather
+// than silently being folded into a "hit".
+func TestContainerStatsCountsLazyReloadAsLoad(t *testing.T) {
+	var calls int32
+	wantErr := errors.New("boom")
+	c := Must(New(func(key string) (string, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n == 2 {
+			return "", wantErr
+		}
+		return key, nil
+	}, 10*time.Millisecond))
+
+	if _, err := c.Get("a"); err != nil {
+		t.Fatalf("first Get() err = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// The entry for "a" still exists in the policy but is TTL-expired, so
+	// this call must perform a second, real fn invocation.
+	if _, err := c.Get("a"); !errors.Is(err, wantErr) {
+		t.Fatalf("lazily-reloaded Get() err = %v, want %v", err, wantErr)
+	}
+
+	s := c.Stats()
+	if s.Loads != 2 {
+		t.Errorf("Loads = %d, want 2 (initial load + lazy reload)", s.Loads)
+	}
+	if s.LoadErrors != 1 {
+		t.Errorf("LoadErrors = %d, want 1 (the lazy reload's error)", s.LoadErrors)
+	}
+	if s.Misses != 2 {
+		t.Errorf("Misses = %d, want 2: an expired entry still requires a fresh load", s.Misses)
+	}
+	if s.Hits != 0 {
+		t.Errorf("Hits = %d, want 0", s.Hits)
+	}
+}
+
+func TestContainerPeekAndContains(t *testing.T) {
+	c := Must(New(func(key string) (string, error) {
+		return key, nil
+	}, time.Minute))
+
+	if _, ok := c.Peek("k"); ok {
+		t.Fatalf("Peek(k) ok = true before any Get, want false")
+	}
+	if c.Contains("k") {
+		t.Fatalf("Contains(k) = true before any Get, want false")
+	}
+
+	c.Get("k")
+
+	val, ok := c.Peek("k")
+	if !ok || val != "k" {
+		t.Fatalf("Peek(k) = %v, %v, want k, true", val, ok)
+	}
+	if !c.Contains("k") {
+		t.Fatalf("Contains(k) = false after Get, want true")
+	}
+
+	// Peek must not itself trigger a load or record an access.
+	if _, ok := c.Peek("other"); ok {
+		t.Fatalf("Peek(other) ok = true, want false (Peek must not load)")
+	}
+}
+
+func TestContainerKeys(t *testing.T) {
+	c := Must(New(func(key string) (string, error) {
+		return key, nil
+	}, time.Minute))
+
+	c.Get("a")
+	c.Get("b")
+
+	keys := c.Keys()
+	if len(keys) != 2 {
+		t.Fatalf("Keys() = %v, want 2 entries", keys)
+	}
+	seen := map[interface{}]bool{}
+	for _, k := range keys {
+		seen[k] = true
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Fatalf("Keys() = %v, want both a and b", keys)
+	}
+}
+
+func TestContainerInvalidateFn(t *testing.T) {
+	c := Must(New(func(key string) (string, error) {
+		return key, nil
+	}, time.Minute))
+
+	c.Get("keep")
+	c.Get("drop-1")
+	c.Get("drop-2")
+
+	n := c.InvalidateFn(func(params []interface{}) bool {
+		key, _ := params[0].(string)
+		return len(key) >= len("drop-1")
+	})
+	if n != 2 {
+		t.Fatalf("InvalidateFn removed %d entries, want 2", n)
+	}
+	if !c.Contains("keep") {
+		t.Fatalf("Contains(keep) = false, want true")
+	}
+	if c.Contains("drop-1") || c.Contains("drop-2") {
+		t.Fatalf("drop-1/drop-2 still present after InvalidateFn")
+	}
+}
@@ -0,0 +1,363 @@
+package lcache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheOption configures optional behavior of a Cache constructed with
+// NewCacheWithOptions, mirroring Option for Container.
+type CacheOption[K comparable, V any] func(*cacheOptions[K, V])
+
+// cacheOptions holds the optional behavior configured through CacheOption
+// values passed to NewCacheWithOptions.
+type cacheOptions[K comparable, V any] struct {
+	errTTL               time.Duration
+	hasErrTTL            bool
+	staleWhileRevalidate bool
+	policy               EvictionPolicy
+	cleanupInterval      time.Duration
+	onEvict              func(key K, value V)
+}
+
+// CacheErrorTTL mirrors ErrorTTL for a generics-based Cache.
+func CacheErrorTTL[K comparable, V any](d time.Duration) CacheOption[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.errTTL = d
+		o.hasErrTTL = true
+	}
+}
+
+// CacheStaleWhileRevalidate mirrors StaleWhileRevalidate for a
+// generics-based Cache.
+func CacheStaleWhileRevalidate[K comparable, V any]() CacheOption[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.staleWhileRevalidate = true
+	}
+}
+
+// CachePolicy mirrors Policy for a generics-based Cache.
+func CachePolicy[K comparable, V any](p EvictionPolicy) CacheOption[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.policy = p
+	}
+}
+
+// CacheCleanupInterval mirrors CleanupInterval for a generics-based Cache.
+func CacheCleanupInterval[K comparable, V any](d time.Duration) CacheOption[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.cleanupInterval = d
+	}
+}
+
+// CacheOnEvict mirrors OnEvict for a generics-based Cache.
+func CacheOnEvict[K comparable, V any](fn func(key K, value V)) CacheOption[K, V] {
+	return func(o *cacheOptions[K, V]) {
+		o.onEvict = fn
+	}
+}
+
+// Cache is a typed alternative to Container for callers who know K and V at
+// compile time. Unlike Container, which stores its loader as an
+// interface{} and dispatches every miss through reflect.ValueOf/f.Call(in)
+// in reflectCall, Cache closes over loader directly, so Get never allocates
+// a []reflect.Value or pays reflect.Call's dispatch cost. It shares
+// Container's EvictionPolicy implementations, TTL handling, singleflight
+// load dedup, and Stats counters, since all of that is keyed and valued on
+// plain interface{} internally regardless of which type wraps it.
+type Cache[K comparable, V any] struct {
+	sync.RWMutex
+	loader               func(K) (V, error)
+	ttl                  time.Duration
+	errTTL               time.Duration
+	hasErrTTL            bool
+	staleWhileRevalidate bool
+	policy               EvictionPolicy
+	onEvict              func(key K, value V)
+	closeOnce            sync.Once
+	closeCh              chan struct{}
+	stats                statsCounters
+}
+
+// NewCache constructs a Cache of DefaultCapacity backed by loader, with
+// entries expiring ttl after being loaded.
+func NewCache[K comparable, V any](loader func(K) (V, error), ttl time.Duration) *Cache[K, V] {
+	return newCache[K, V](DefaultCapacity, loader, ttl, nil)
+}
+
+// NewCacheWithSize constructs a Cache of the given capacity backed by loader.
+func NewCacheWithSize[K comparable, V any](size int, loader func(K) (V, error), ttl time.Duration) *Cache[K, V] {
+	return newCache[K, V](size, loader, ttl, nil)
+}
+
+// NewCacheWithOptions constructs a Cache of DefaultCapacity backed by loader,
+// with optional behavior enabled through opts, such as CacheErrorTTL,
+// CacheStaleWhileRevalidate, or CachePolicy. When CachePolicy is supplied,
+// the cache uses that policy's own capacity instead of DefaultCapacity.
+func NewCacheWithOptions[K comparable, V any](loader func(K) (V, error), ttl time.Duration, opts ...CacheOption[K, V]) *Cache[K, V] {
+	return newCache[K, V](DefaultCapacity, loader, ttl, opts)
+}
+
+func newCache[K comparable, V any](size int, loader func(K) (V, error), ttl time.Duration, opts []CacheOption[K, V]) *Cache[K, V] {
+	var o cacheOptions[K, V]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	policy := o.policy
+	if policy == nil {
+		policy = NewLRUPolicy(size)
+	}
+	c := &Cache[K, V]{
+		loader:               loader,
+		ttl:                  ttl,
+		errTTL:               o.errTTL,
+		hasErrTTL:            o.hasErrTTL,
+		staleWhileRevalidate: o.staleWhileRevalidate,
+		policy:               policy,
+		onEvict:              o.onEvict,
+		closeCh:              make(chan struct{}),
+	}
+	if o.cleanupInterval > 0 {
+		go c.runJanitor(o.cleanupInterval)
+	}
+	return c
+}
+
+// Get returns the value for key. If key has in the cache, it will return
+// immediately. Otherwise, it will load a value by calling loader.
+func (c *Cache[K, V]) Get(key K) (V, error) {
+	c.Lock()
+	if itm, ok := c.policy.Get(key); ok {
+		c.Unlock()
+		val, err, hit := itemValue[V](itm)
+		if hit {
+			atomic.AddUint64(&c.stats.hits, 1)
+		} else {
+			atomic.AddUint64(&c.stats.misses, 1)
+		}
+		return val, err
+	}
+
+	loader := c.loader
+	itm := newItem(key, nil, c.ttl, c.errTTL, c.hasErrTTL, c.staleWhileRevalidate, func() (interface{}, error) {
+		return loader(key)
+	}, c.recordLoad)
+	_, evictedItem, evicted := c.policy.Add(key, itm)
+	c.Unlock()
+
+	atomic.AddUint64(&c.stats.misses, 1)
+	if evicted {
+		atomic.AddUint64(&c.stats.evictions, 1)
+	}
+	c.notifyEvict(evictedItem, evicted)
+
+	val, err, _ := itemValue[V](itm)
+	return val, err
+}
+
+// recordLoad mirrors Container.recordLoad for a generics-based Cache.
+func (c *Cache[K, V]) recordLoad(err error) {
+	atomic.AddUint64(&c.stats.loads, 1)
+	if err != nil {
+		atomic.AddUint64(&c.stats.loadErrors, 1)
+	}
+}
+
+// itemValue waits for itm to finish loading and asserts its value back to V.
+func itemValue[V any](itm *item) (value V, err error, hit bool) {
+	val, err, hit := itm.Value()
+	v, _ := val.(V)
+	return v, err, hit
+}
+
+// Purge is used to completely clear the cache.
+func (c *Cache[K, V]) Purge() {
+	c.Lock()
+	var evicted []*item
+	for c.policy.Len() > 0 {
+		_, itm, ok := c.policy.Evict()
+		if !ok {
+			break
+		}
+		evicted = append(evicted, itm)
+	}
+	c.Unlock()
+
+	if len(evicted) > 0 {
+		atomic.AddUint64(&c.stats.evictions, uint64(len(evicted)))
+	}
+	for _, itm := range evicted {
+		c.notifyEvict(itm, true)
+	}
+}
+
+// Remove removes key from the cache, returning if it was contained.
+func (c *Cache[K, V]) Remove(key K) bool {
+	c.Lock()
+	itm, existed := c.policy.Get(key)
+	if existed {
+		c.policy.Remove(key)
+	}
+	c.Unlock()
+
+	if existed {
+		atomic.AddUint64(&c.stats.evictions, 1)
+		c.notifyEvict(itm, true)
+	}
+	return existed
+}
+
+// Peek returns the cached value for key without recording an access for the
+// eviction policy or triggering a load, reporting ok=false if no value has
+// been loaded for key yet.
+func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
+	c.RLock()
+	itm, found := c.policy.Peek(key)
+	c.RUnlock()
+	if !found {
+		return value, false
+	}
+	val, loaded := itm.Peek()
+	if !loaded {
+		return value, false
+	}
+	v, _ := val.(V)
+	return v, true
+}
+
+// Contains reports whether key currently has a loaded entry in the cache,
+// without recording an access or triggering a load.
+func (c *Cache[K, V]) Contains(key K) bool {
+	_, ok := c.Peek(key)
+	return ok
+}
+
+// Keys returns the keys of all entries currently held by the cache, in no
+// particular order.
+func (c *Cache[K, V]) Keys() []K {
+	c.RLock()
+	defer c.RUnlock()
+	snap := c.policy.Snapshot()
+	keys := make([]K, 0, len(snap))
+	for key := range snap {
+		if k, ok := key.(K); ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// InvalidateFn removes every entry whose key satisfies fn, invoking OnEvict
+// for each one removed, and returns the number removed.
+func (c *Cache[K, V]) InvalidateFn(fn func(key K) bool) int {
+	c.Lock()
+	var evicted []*item
+	for key, itm := range c.policy.Snapshot() {
+		k, _ := key.(K)
+		if !fn(k) {
+			continue
+		}
+		c.policy.Remove(key)
+		evicted = append(evicted, itm)
+	}
+	c.Unlock()
+
+	if len(evicted) > 0 {
+		atomic.AddUint64(&c.stats.evictions, uint64(len(evicted)))
+	}
+	for _, itm := range evicted {
+		c.notifyEvict(itm, true)
+	}
+	return len(evicted)
+}
+
+// DeleteExpired sweeps the cache for entries whose TTL has elapsed and
+// removes them, invoking OnEvict for each. It runs automatically on
+// CacheCleanupInterval when configured, but can also be called manually.
+func (c *Cache[K, V]) DeleteExpired() {
+	now := time.Now()
+
+	c.Lock()
+	var evicted []*item
+	for key, itm := range c.policy.Snapshot() {
+		itm.mu.Lock()
+		// See Container.DeleteExpired: an item mid-refresh under
+		// CacheStaleWhileRevalidate is loaded with a stale, past expire, so
+		// skip it here too rather than orphaning the in-flight refresh.
+		expired := itm.loaded && !itm.loading && now.After(itm.expire)
+		itm.mu.Unlock()
+		if !expired {
+			continue
+		}
+		c.policy.Remove(key)
+		evicted = append(evicted, itm)
+	}
+	c.Unlock()
+
+	if len(evicted) > 0 {
+		atomic.AddUint64(&c.stats.evictions, uint64(len(evicted)))
+	}
+	for _, itm := range evicted {
+		c.notifyEvict(itm, true)
+	}
+}
+
+// Close stops the background janitor goroutine started via
+// CacheCleanupInterval. It is safe to call more than once, and safe to call
+// on a Cache that was never given a CacheCleanupInterval.
+func (c *Cache[K, V]) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+	})
+}
+
+// runJanitor periodically calls DeleteExpired until Close is called.
+func (c *Cache[K, V]) runJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.DeleteExpired()
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+// notifyEvict invokes the configured OnEvict callback for itm, if any. It
+// must be called without the cache lock held.
+func (c *Cache[K, V]) notifyEvict(itm *item, evicted bool) {
+	if !evicted || c.onEvict == nil {
+		return
+	}
+	itm.mu.Lock()
+	key, _ := itm.key.(K)
+	value, _ := itm.value.(V)
+	itm.mu.Unlock()
+	c.onEvict(key, value)
+}
+
+// Len returns the number of items in the cache.
+func (c *Cache[K, V]) Len() int {
+	c.RLock()
+	defer c.RUnlock()
+	return c.policy.Len()
+}
+
+// Stats returns a snapshot of the cache's cumulative hit/miss/eviction
+// counters and current size.
+func (c *Cache[K, V]) Stats() Stats {
+	c.RLock()
+	size := c.policy.Len()
+	c.RUnlock()
+	return Stats{
+		Hits:       atomic.LoadUint64(&c.stats.hits),
+		Misses:     atomic.LoadUint64(&c.stats.misses),
+		Evictions:  atomic.LoadUint64(&c.stats.evictions),
+		Loads:      atomic.LoadUint64(&c.stats.loads),
+		LoadErrors: atomic.LoadUint64(&c.stats.loadErrors),
+		Size:       size,
+	}
+}
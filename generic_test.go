@@ -0,0 +1,155 @@
+package lcache
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheGetLoadsAndCaches(t *testing.T) {
+	var calls int32
+	c := NewCache(func(key string) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return len(key), nil
+	}, time.Minute)
+
+	v, err := c.Get("hello")
+	if err != nil || v != 5 {
+		t.Fatalf("Get() = %v, %v, want 5, nil", v, err)
+	}
+	if _, err := c.Get("hello"); err != nil {
+		t.Fatalf("second Get() err = %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("loader called %d times, want 1", got)
+	}
+}
+
+func TestCachePeekContainsRemove(t *testing.T) {
+	c := NewCache(func(key string) (string, error) {
+		return key, nil
+	}, time.Minute)
+
+	if c.Contains("k") {
+		t.Fatalf("Contains(k) = true before Get, want false")
+	}
+
+	c.Get("k")
+	if !c.Contains("k") {
+		t.Fatalf("Contains(k) = false after Get, want true")
+	}
+	if val, ok := c.Peek("k"); !ok || val != "k" {
+		t.Fatalf("Peek(k) = %v, %v, want k, true", val, ok)
+	}
+
+	if !c.Remove("k") {
+		t.Fatalf("Remove(k) = false, want true")
+	}
+	if c.Contains("k") {
+		t.Fatalf("Contains(k) = true after Remove, want false")
+	}
+}
+
+func TestCacheKeysAndInvalidateFn(t *testing.T) {
+	c := NewCache(func(key int) (int, error) {
+		return key * key, nil
+	}, time.Minute)
+
+	c.Get(1)
+	c.Get(2)
+	c.Get(3)
+
+	keys := c.Keys()
+	if len(keys) != 3 {
+		t.Fatalf("Keys() = %v, want 3 entries", keys)
+	}
+
+	n := c.InvalidateFn(func(key int) bool { return key%2 == 0 })
+	if n != 1 {
+		t.Fatalf("InvalidateFn removed %d, want 1", n)
+	}
+	if c.Contains(2) {
+		t.Fatalf("Contains(2) = true after InvalidateFn, want false")
+	}
+	if !c.Contains(1) || !c.Contains(3) {
+		t.Fatalf("InvalidateFn removed an entry it shouldn't have")
+	}
+}
+
+func TestCacheEvictsOverCapacity(t *testing.T) {
+	var evictedKeys []int
+	c := NewCacheWithOptions(func(key int) (int, error) {
+		return key, nil
+	}, time.Minute,
+		CacheCleanupInterval[int, int](0),
+		CachePolicy[int, int](NewLRUPolicy(2)),
+		CacheOnEvict(func(key int, value int) {
+			evictedKeys = append(evictedKeys, key)
+		}),
+	)
+
+	c.Get(1)
+	c.Get(2)
+	c.Get(3)
+
+	if len(evictedKeys) != 1 || evictedKeys[0] != 1 {
+		t.Fatalf("evictedKeys = %v, want [1]", evictedKeys)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestCacheDeleteExpiredSkipsInFlightRefresh(t *testing.T) {
+	unblock := make(chan struct{})
+	var calls int32
+	c := NewCacheWithOptions(func(key string) (string, error) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			<-unblock
+		}
+		return key, nil
+	}, 10*time.Millisecond, CacheStaleWhileRevalidate[string, string]())
+
+	if _, err := c.Get("k"); err != nil {
+		t.Fatalf("first Get() err = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := c.Get("k"); err != nil {
+		t.Fatalf("stale Get() err = %v", err)
+	}
+
+	c.DeleteExpired()
+	if got := c.Len(); got != 1 {
+		t.Fatalf("Len() after sweep during in-flight refresh = %d, want 1", got)
+	}
+
+	close(unblock)
+}
+
+// TestCacheStatsCountsLazyReloadAsLoad mirrors
+// TestContainerStatsCountsLazyReloadAsLoad: Cache[K, V].Get duplicates
+// Container.Get's hit/miss and load accounting, so it must not count a
+// TTL-expired entry's synchronous reload as a hit with no load.
+func TestCacheStatsCountsLazyReloadAsLoad(t *testing.T) {
+	var calls int32
+	c := NewCache(func(key string) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return key, nil
+	}, 10*time.Millisecond)
+
+	c.Get("a")
+	time.Sleep(20 * time.Millisecond)
+	c.Get("a")
+
+	s := c.Stats()
+	if s.Loads != 2 {
+		t.Errorf("Loads = %d, want 2 (initial load + lazy reload)", s.Loads)
+	}
+	if s.Misses != 2 {
+		t.Errorf("Misses = %d, want 2: an expired entry still requires a fresh load", s.Misses)
+	}
+	if s.Hits != 0 {
+		t.Errorf("Hits = %d, want 0", s.Hits)
+	}
+}
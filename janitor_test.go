@@ -0,0 +1,106 @@
+package lcache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestContainerDeleteExpiredRemovesStaleEntries(t *testing.T) {
+	c := Must(New(func(key string) (string, error) {
+		return key, nil
+	}, 10*time.Millisecond))
+
+	if _, err := c.Get("k"); err != nil {
+		t.Fatalf("Get() err = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	c.DeleteExpired()
+
+	if got := c.Len(); got != 0 {
+		t.Fatalf("Len() after DeleteExpired = %d, want 0", got)
+	}
+}
+
+func TestContainerDeleteExpiredSkipsInFlightRefresh(t *testing.T) {
+	unblock := make(chan struct{})
+	var calls int32
+	c := Must(NewWithOptions(func(key string) (string, error) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			// Only the background refresh (the second call) blocks; the
+			// initial load must return immediately or Get would deadlock.
+			<-unblock
+		}
+		return key, nil
+	}, 10*time.Millisecond, StaleWhileRevalidate()))
+
+	if _, err := c.Get("k"); err != nil {
+		t.Fatalf("first Get() err = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// This Get serves the stale value and kicks off a background refresh
+	// that blocks on unblock, leaving the item loaded with a past expire.
+	if _, err := c.Get("k"); err != nil {
+		t.Fatalf("stale Get() err = %v", err)
+	}
+
+	c.DeleteExpired()
+	if got := c.Len(); got != 1 {
+		t.Fatalf("Len() after sweep during in-flight refresh = %d, want 1 (item should survive)", got)
+	}
+
+	close(unblock)
+}
+
+func TestContainerJanitorEvictsExpiredEntries(t *testing.T) {
+	var mu sync.Mutex
+	var evictedParams []interface{}
+
+	c := Must(NewWithOptions(func(key string) (string, error) {
+		return key, nil
+	}, 10*time.Millisecond,
+		CleanupInterval(10*time.Millisecond),
+		OnEvict(func(params []interface{}, value interface{}) {
+			mu.Lock()
+			evictedParams = append(evictedParams, params[0])
+			mu.Unlock()
+		}),
+	))
+	defer c.Close()
+
+	if _, err := c.Get("k"); err != nil {
+		t.Fatalf("Get() err = %v", err)
+	}
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(evictedParams)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(evictedParams) == 0 {
+		t.Fatalf("janitor never evicted the expired entry")
+	}
+	if evictedParams[0] != "k" {
+		t.Fatalf("OnEvict params[0] = %v, want k", evictedParams[0])
+	}
+}
+
+func TestContainerCloseIsIdempotentAndSafeWithoutJanitor(t *testing.T) {
+	c := Must(New(func(key string) (string, error) {
+		return key, nil
+	}, time.Minute))
+
+	c.Close()
+	c.Close()
+}
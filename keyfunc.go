@@ -0,0 +1,67 @@
+package lcache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+)
+
+// KeyFunc derives the string cache key for a call from its params. It is
+// only consulted when Container's single-comparable-argument fast path
+// doesn't apply (see NewWithOptions/WithKeyFunc), since that path keys
+// directly on the argument instead.
+type KeyFunc func(params []interface{}) string
+
+// DefaultKeyFunc is the KeyFunc used when WithKeyFunc is not supplied. It
+// length-prefixes each param's fmt representation before concatenating them,
+// so no delimiter inside one param can be mistaken for the boundary between
+// params: the old "#"-joined scheme this replaced let ["#", ""] and ["",
+// "#"] collide on the same key.
+func DefaultKeyFunc(params []interface{}) string {
+	var buf bytes.Buffer
+	for _, param := range params {
+		// convert pointer to reference value
+		s := fmt.Sprintf("%v", reflect.Indirect(reflect.ValueOf(param)))
+		fmt.Fprintf(&buf, "%d:%s", len(s), s)
+	}
+	return buf.String()
+}
+
+// GobKeyFunc derives a key by gob-encoding params in order. It avoids fmt's
+// verb-specific formatting, but concrete param types other than builtins
+// must be registered with gob.Register for Encode to recognize them; params
+// that fail to encode fall back to DefaultKeyFunc.
+func GobKeyFunc(params []interface{}) string {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	for _, param := range params {
+		if err := enc.Encode(&param); err != nil {
+			return DefaultKeyFunc(params)
+		}
+	}
+	return buf.String()
+}
+
+// JSONKeyFunc derives a key by JSON-marshaling params together. It suits
+// param types gob can't encode without registration, provided they marshal
+// to JSON deterministically (map key order included); params that fail to
+// marshal fall back to DefaultKeyFunc.
+func JSONKeyFunc(params []interface{}) string {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return DefaultKeyFunc(params)
+	}
+	return string(b)
+}
+
+// FNVKeyFunc hashes the DefaultKeyFunc serialization down to a fixed 8-byte
+// key with FNV-1a, trading a vanishingly small chance of collision for a
+// constant-size key regardless of how large or numerous params are.
+func FNVKeyFunc(params []interface{}) string {
+	h := fnv.New64a()
+	h.Write([]byte(DefaultKeyFunc(params)))
+	return string(h.Sum(nil))
+}
@@ -0,0 +1,79 @@
+package lcache
+
+import "testing"
+
+func TestDefaultKeyFuncAvoidsDelimiterCollisions(t *testing.T) {
+	k1 := DefaultKeyFunc([]interface{}{"#", ""})
+	k2 := DefaultKeyFunc([]interface{}{"", "#"})
+	if k1 == k2 {
+		t.Fatalf("DefaultKeyFunc(%q) == DefaultKeyFunc(%q), want distinct keys", k1, k2)
+	}
+}
+
+func TestWithKeyFuncIsUsedForMultiArgFn(t *testing.T) {
+	var calls int
+	c := Must(NewWithOptions(func(a, b string) (string, error) {
+		return a + b, nil
+	}, 0, WithKeyFunc(func(params []interface{}) string {
+		calls++
+		return DefaultKeyFunc(params)
+	})))
+
+	if _, err := c.Get("x", "y"); err != nil {
+		t.Fatalf("Get() err = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("custom KeyFunc called %d times, want 1", calls)
+	}
+}
+
+func TestFastPathSkipsKeyFuncForSingleComparableArg(t *testing.T) {
+	var calls int
+	c := Must(NewWithOptions(func(key int) (int, error) {
+		return key * 2, nil
+	}, 0, WithKeyFunc(func(params []interface{}) string {
+		calls++
+		return DefaultKeyFunc(params)
+	})))
+
+	if _, err := c.Get(21); err != nil {
+		t.Fatalf("Get() err = %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("KeyFunc called %d times, want 0 since the fast path should skip it", calls)
+	}
+}
+
+func TestGetWithInterfaceArgDoesNotPanicOnUncomparableValue(t *testing.T) {
+	c := Must(New(func(key interface{}) (int, error) {
+		s, ok := key.([]int)
+		if !ok {
+			return 0, nil
+		}
+		return len(s), nil
+	}, 0))
+
+	// A bare interface{} parameter reports Comparable() == true via
+	// reflect even though the dynamic value it holds here, a slice, is
+	// not hashable at runtime. Get must not take the fast path for it.
+	n, err := c.Get([]int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Get() err = %v, want nil", err)
+	}
+	if n != 3 {
+		t.Fatalf("Get() = %v, want 3", n)
+	}
+}
+
+func TestRemoveWithWrongArityDoesNotPanic(t *testing.T) {
+	c := Must(New(func(key string) (string, error) {
+		return key, nil
+	}, 0))
+
+	if c.Remove() {
+		t.Fatalf("Remove() with no params = true, want false")
+	}
+	if c.Remove("a", "b") {
+		t.Fatalf("Remove() with too many params = true, want false")
+	}
+}
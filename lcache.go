@@ -1,12 +1,10 @@
 package lcache
 
 import (
-	"bytes"
-	"container/list"
 	"errors"
-	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -29,19 +27,26 @@ var (
 // Container implements a thread-safe cache container
 type Container struct {
 	sync.RWMutex
-	capacity  int
-	fn        interface{}
-	fnKind    reflect.Kind
-	fnNumIn   int
-	fnNumOut  int
-	ttl       time.Duration
-	items     map[string]*list.Element
-	evictList *list.List
+	fn                   interface{}
+	fnKind               reflect.Kind
+	fnNumIn              int
+	fnNumOut             int
+	ttl                  time.Duration
+	errTTL               time.Duration
+	hasErrTTL            bool
+	staleWhileRevalidate bool
+	policy               EvictionPolicy
+	keyFunc              KeyFunc
+	fastKey              bool
+	onEvict              func(params []interface{}, value interface{})
+	closeOnce            sync.Once
+	closeCh              chan struct{}
+	stats                statsCounters
 }
 
 // New create a cache container with default capacity and given parameters.
 func New(fn interface{}, ttl time.Duration) (*Container, error) {
-	return newContainer(DefaultCapacity, fn, ttl)
+	return newContainer(DefaultCapacity, fn, ttl, nil)
 }
 
 // NewWithSize constructs a cache container with the given parameters.
@@ -49,7 +54,15 @@ func NewWithSize(size int, fn interface{}, ttl time.Duration) (*Container, error
 	if size < 0 {
 		return nil, errors.New("Must provide a positive size")
 	}
-	return newContainer(size, fn, ttl)
+	return newContainer(size, fn, ttl, nil)
+}
+
+// NewWithOptions constructs a cache container with default capacity, the
+// given parameters, and optional behavior enabled through opts, such as
+// ErrorTTL, StaleWhileRevalidate, or Policy. When Policy is supplied, the
+// container uses that policy's own capacity instead of DefaultCapacity.
+func NewWithOptions(fn interface{}, ttl time.Duration, opts ...Option) (*Container, error) {
+	return newContainer(DefaultCapacity, fn, ttl, opts)
 }
 
 // Must is a helper that wraps a call to a function returning (*Container, error)
@@ -63,189 +76,469 @@ func Must(c *Container, err error) *Container {
 	return c
 }
 
-func newContainer(size int, fn interface{}, ttl time.Duration) (*Container, error) {
+func newContainer(size int, fn interface{}, ttl time.Duration, opts []Option) (*Container, error) {
 	t := reflect.TypeOf(fn)
 	if t.Kind() != reflect.Func || t.NumOut() != 2 {
 		return nil, ErrInvalidFn
 	}
+	var o containerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	policy := o.policy
+	if policy == nil {
+		policy = NewLRUPolicy(size)
+	}
+	keyFunc := o.keyFunc
+	if keyFunc == nil {
+		keyFunc = DefaultKeyFunc
+	}
 	c := &Container{
-		capacity:  size,
-		fn:        fn,
-		fnKind:    t.Kind(),
-		fnNumIn:   t.NumIn(),
-		fnNumOut:  t.NumOut(),
-		ttl:       ttl,
-		items:     make(map[string]*list.Element),
-		evictList: list.New(),
+		fn:                   fn,
+		fnKind:               t.Kind(),
+		fnNumIn:              t.NumIn(),
+		fnNumOut:             t.NumOut(),
+		ttl:                  ttl,
+		errTTL:               o.errTTL,
+		hasErrTTL:            o.hasErrTTL,
+		staleWhileRevalidate: o.staleWhileRevalidate,
+		policy:               policy,
+		keyFunc:              keyFunc,
+		// fastKey skips KeyFunc entirely for single-argument fn, keying the
+		// policy directly on the argument the same way bluele/gcache and
+		// hashicorp/golang-lru key single-value caches. Interface-typed
+		// params are excluded even though reflect reports them Comparable:
+		// that only guarantees the interface itself is comparable, not
+		// whatever dynamic value (a slice, map, or func) ends up stored in
+		// it, and indexing a policy map on one of those panics at runtime.
+		fastKey: t.NumIn() == 1 && t.In(0).Kind() != reflect.Interface && t.In(0).Comparable(),
+		onEvict: o.onEvict,
+		closeCh: make(chan struct{}),
+	}
+	if o.cleanupInterval > 0 {
+		go c.runJanitor(o.cleanupInterval)
 	}
 	return c, nil
 }
 
-// generateUniqueKey generates unique key with paramters.
-func generateUniqueKey(params ...interface{}) string {
-	// generate unique key
-	buf := bytes.NewBufferString("")
-	// FIXME: ["#" ""] and ["" "#"] will generate same key
-	for _, param := range params {
-		// convert pointer to reference value
-		buf.WriteString(fmt.Sprintf("#%v", reflect.Indirect(reflect.ValueOf(param))))
+// cacheKey derives the Policy key for a call's params: the bare param itself
+// when the single-comparable-argument fast path applies, skipping
+// serialization entirely, or the configured KeyFunc's string otherwise.
+func (c *Container) cacheKey(params []interface{}) interface{} {
+	if c.fastKey {
+		return params[0]
 	}
-	return buf.String()
+	return c.keyFunc(params)
 }
 
 // Get is used to obtain the value with the given parameters. If the params string
 // has in the container, it will return immediately. Otherwise, it will load data
 // with the fn callback.
+//
+// The container lock is only held while looking up or inserting the entry in
+// the configured Policy; the (possibly slow) load itself, and any wait for
+// it, happens outside the lock so a single slow fn call can't stall
+// unrelated Get calls.
 func (c *Container) Get(params ...interface{}) (interface{}, error) {
 	// check params
 	if len(params) != c.fnNumIn {
 		return nil, ErrFnParams
 	}
 
+	key := c.cacheKey(params)
+
 	c.Lock()
-	defer c.Unlock()
-	key := generateUniqueKey(params...)
-	ent, ok := c.items[key]
-	if ok {
-		c.evictList.MoveToFront(ent)
-		return ent.Value.(*item).Value()
+	if itm, ok := c.policy.Get(key); ok {
+		c.Unlock()
+		val, err, hit := itm.Value()
+		if hit {
+			atomic.AddUint64(&c.stats.hits, 1)
+		} else {
+			atomic.AddUint64(&c.stats.misses, 1)
+		}
+		return val, err
 	}
 
-	itm := newItem(params, key, c.ttl, c.fn)
-	ent = c.evictList.PushFront(itm)
-	c.items[key] = ent
+	fn := c.fn
+	itm := newItem(key, params, c.ttl, c.errTTL, c.hasErrTTL, c.staleWhileRevalidate, func() (interface{}, error) {
+		return reflectCall(fn, params)
+	}, c.recordLoad)
+	_, evictedItem, evicted := c.policy.Add(key, itm)
+	c.Unlock()
 
-	evict := c.evictList.Len() > c.capacity
-	if evict {
-		c.removeOldest()
+	atomic.AddUint64(&c.stats.misses, 1)
+	if evicted {
+		atomic.AddUint64(&c.stats.evictions, 1)
 	}
-	return itm.Value()
-}
+	c.notifyEvict(evictedItem, evicted)
 
-// removeOldest removes the oldest item from the container.
-func (c *Container) removeOldest() {
-	ent := c.evictList.Back()
-	if ent != nil {
-		c.removeElement(ent)
-	}
+	val, err, _ := itm.Value()
+	return val, err
 }
 
-// removeElement is used to remove a given list element from the container.
-func (c *Container) removeElement(e *list.Element) {
-	c.evictList.Remove(e)
-	itm := e.Value.(*item)
-	delete(c.items, itm.key)
+// recordLoad updates the load/load-error counters for a single completed fn
+// invocation. It is item's onLoad callback, so it fires exactly once per
+// real fn call: the initial load, a lazy reload of an expired entry, or a
+// StaleWhileRevalidate background refresh alike — regardless of whether the
+// Get call that observed the entry counted as a hit or a miss.
+func (c *Container) recordLoad(err error) {
+	atomic.AddUint64(&c.stats.loads, 1)
+	if err != nil {
+		atomic.AddUint64(&c.stats.loadErrors, 1)
+	}
 }
 
 // Purge is used to completely clear the container
 func (c *Container) Purge() {
 	c.Lock()
-	defer c.Unlock()
-	for key := range c.items {
-		delete(c.items, key)
+	var evicted []*item
+	for c.policy.Len() > 0 {
+		_, itm, ok := c.policy.Evict()
+		if !ok {
+			break
+		}
+		evicted = append(evicted, itm)
+	}
+	c.Unlock()
+
+	if len(evicted) > 0 {
+		atomic.AddUint64(&c.stats.evictions, uint64(len(evicted)))
+	}
+	for _, itm := range evicted {
+		c.notifyEvict(itm, true)
 	}
-	c.evictList.Init()
 }
 
 // Remove removes the provided params from the container, returning if the
 // params key was contained.
 func (c *Container) Remove(params ...interface{}) bool {
-	key := generateUniqueKey(params...)
+	if len(params) != c.fnNumIn {
+		return false
+	}
+	key := c.cacheKey(params)
+	c.Lock()
+	itm, existed := c.policy.Get(key)
+	if existed {
+		c.policy.Remove(key)
+	}
+	c.Unlock()
+
+	if existed {
+		atomic.AddUint64(&c.stats.evictions, 1)
+		c.notifyEvict(itm, true)
+	}
+	return existed
+}
+
+// Peek returns the cached value for params without recording an access for
+// the eviction policy or triggering a load, reporting ok=false if no value
+// has been loaded for params yet.
+func (c *Container) Peek(params ...interface{}) (value interface{}, ok bool) {
+	if len(params) != c.fnNumIn {
+		return nil, false
+	}
+	key := c.cacheKey(params)
+
+	c.RLock()
+	itm, found := c.policy.Peek(key)
+	c.RUnlock()
+	if !found {
+		return nil, false
+	}
+	return itm.Peek()
+}
+
+// Contains reports whether params currently has a loaded entry in the
+// container, without recording an access or triggering a load.
+func (c *Container) Contains(params ...interface{}) bool {
+	_, ok := c.Peek(params...)
+	return ok
+}
+
+// Keys returns the opaque cache keys of all entries currently held by the
+// container, in no particular order. Each key is whatever cacheKey derived
+// for the call it was stored under: the call's single argument under the
+// fast path, or the configured KeyFunc's string otherwise. Since the fast
+// path can key directly on a non-string argument, Keys returns
+// []interface{} rather than []string.
+func (c *Container) Keys() []interface{} {
+	c.RLock()
+	defer c.RUnlock()
+	snap := c.policy.Snapshot()
+	keys := make([]interface{}, 0, len(snap))
+	for key := range snap {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// InvalidateFn removes every entry whose original call params satisfy fn,
+// invoking OnEvict for each one removed, and returns the number removed.
+func (c *Container) InvalidateFn(fn func(params []interface{}) bool) int {
+	c.Lock()
+	var evicted []*item
+	for key, itm := range c.policy.Snapshot() {
+		itm.mu.Lock()
+		params := itm.params
+		itm.mu.Unlock()
+		if !fn(params) {
+			continue
+		}
+		c.policy.Remove(key)
+		evicted = append(evicted, itm)
+	}
+	c.Unlock()
+
+	if len(evicted) > 0 {
+		atomic.AddUint64(&c.stats.evictions, uint64(len(evicted)))
+	}
+	for _, itm := range evicted {
+		c.notifyEvict(itm, true)
+	}
+	return len(evicted)
+}
+
+// Stats returns a snapshot of the container's cumulative hit/miss/eviction
+// counters and current size.
+func (c *Container) Stats() Stats {
+	c.RLock()
+	size := c.policy.Len()
+	c.RUnlock()
+	return Stats{
+		Hits:       atomic.LoadUint64(&c.stats.hits),
+		Misses:     atomic.LoadUint64(&c.stats.misses),
+		Evictions:  atomic.LoadUint64(&c.stats.evictions),
+		Loads:      atomic.LoadUint64(&c.stats.loads),
+		LoadErrors: atomic.LoadUint64(&c.stats.loadErrors),
+		Size:       size,
+	}
+}
+
+// DeleteExpired sweeps the container for entries whose TTL has elapsed and
+// removes them, invoking OnEvict for each. It runs automatically on
+// CleanupInterval when configured, but can also be called manually.
+func (c *Container) DeleteExpired() {
+	now := time.Now()
+
 	c.Lock()
-	defer c.Unlock()
-	if ent, ok := c.items[key]; ok {
-		c.removeElement(ent)
-		return true
+	var evicted []*item
+	for key, itm := range c.policy.Snapshot() {
+		itm.mu.Lock()
+		// A StaleWhileRevalidate item with a background refresh already in
+		// flight is loaded and past its stale expire, but sweeping it now
+		// would orphan that refresh's result: it would land on an *item no
+		// Get can reach anymore, and the next Get would start a redundant
+		// second load. Leave it for the refresh to land and the next sweep
+		// to pick up.
+		expired := itm.loaded && !itm.loading && now.After(itm.expire)
+		itm.mu.Unlock()
+		if !expired {
+			continue
+		}
+		c.policy.Remove(key)
+		evicted = append(evicted, itm)
+	}
+	c.Unlock()
+
+	if len(evicted) > 0 {
+		atomic.AddUint64(&c.stats.evictions, uint64(len(evicted)))
+	}
+	for _, itm := range evicted {
+		c.notifyEvict(itm, true)
+	}
+}
+
+// Close stops the background janitor goroutine started via CleanupInterval.
+// It is safe to call more than once, and safe to call on a Container that
+// was never given a CleanupInterval.
+func (c *Container) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+	})
+}
+
+// runJanitor periodically calls DeleteExpired until Close is called.
+func (c *Container) runJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.DeleteExpired()
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+// notifyEvict invokes the configured OnEvict callback for itm, if any, with
+// the params it was keyed on and its most recently loaded value. It must be
+// called without the container lock held.
+func (c *Container) notifyEvict(itm *item, evicted bool) {
+	if !evicted || c.onEvict == nil {
+		return
 	}
-	return false
+	itm.mu.Lock()
+	params, value := itm.params, itm.value
+	itm.mu.Unlock()
+	c.onEvict(params, value)
 }
 
 // Len returns the number of items in the container
 func (c *Container) Len() int {
 	c.RLock()
 	defer c.RUnlock()
-	return len(c.items)
+	return c.policy.Len()
 }
 
-// item is used to hold a value
+// item is used to hold a value. All reads and writes of value/err/expire go
+// through mu, which also guards the singleflight-style loading state below so
+// concurrent callers for the same key share a single in-flight call to
+// loadFn. loadFn itself is opaque to item: Container closes over a
+// reflect.Call for its reflect-based fn, while Cache[K, V] closes over its
+// typed loader directly, so neither reflection nor params flow through item
+// at all.
 type item struct {
-	key        string
-	params     []interface{}
-	value      interface{}
-	err        error
-	ttl        time.Duration
-	expire     time.Time
-	fn         interface{}
-	initialed  bool
-	initialCh  chan struct{}
-	refreshing bool
-	mu         sync.Mutex
+	key    interface{}
+	params []interface{}
+	loadFn func() (interface{}, error)
+	// onLoad, if non-nil, is called with loadFn's error once after every
+	// completed load, whether it ran synchronously inside Value() or as a
+	// StaleWhileRevalidate background refresh, so a caller (Container,
+	// Cache[K, V]) can count it toward Stats regardless of which path
+	// triggered it.
+	onLoad func(err error)
+
+	ttl                  time.Duration
+	errTTL               time.Duration
+	hasErrTTL            bool
+	staleWhileRevalidate bool
+
+	mu      sync.Mutex
+	value   interface{}
+	err     error
+	expire  time.Time
+	loaded  bool
+	loading bool
+	done    chan struct{}
 }
 
-// newItem constructs an item of the given parameters
-func newItem(params []interface{}, key string, ttl time.Duration, fn interface{}) *item {
+// newItem constructs an item keyed on key, backed by loadFn. params is
+// carried along only so Container's OnEvict/InvalidateFn can hand back the
+// original call arguments; Cache[K, V] passes nil. onLoad may be nil.
+func newItem(key interface{}, params []interface{}, ttl, errTTL time.Duration, hasErrTTL, staleWhileRevalidate bool, loadFn func() (interface{}, error), onLoad func(err error)) *item {
 	return &item{
-		key:       key,
-		params:    params,
-		ttl:       ttl,
-		fn:        fn,
-		initialCh: make(chan struct{}),
+		key:                  key,
+		params:               params,
+		loadFn:               loadFn,
+		ttl:                  ttl,
+		errTTL:               errTTL,
+		hasErrTTL:            hasErrTTL,
+		staleWhileRevalidate: staleWhileRevalidate,
+		onLoad:               onLoad,
 	}
 }
 
-// Value returns the real value in the item. If real value has been loaded,
-// it will return immediately. Otherwise, it will return until the real value
-// is initialed.
-func (i *item) Value() (val interface{}, err error) {
-	if time.Now().Before(i.expire) {
-		return i.value, i.err
-	}
-	i.Refresh()
-	// if item has not initialed, wait until initial done.
-	// else return old value directly
-	if !i.initialed {
-		<-i.initialCh
+// Peek returns the currently cached value without starting or waiting on a
+// load, reporting ok=false if no value has been loaded yet.
+func (i *item) Peek() (value interface{}, ok bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.loaded {
+		return nil, false
 	}
-	return i.value, i.err
+	return i.value, true
 }
 
-// Refresh is used to refresh real value with fn callback.
-func (i *item) Refresh() {
+// Value returns the real value in the item. If a fresh value has already been
+// loaded, it returns immediately. If staleWhileRevalidate is enabled and a
+// stale value is available, that value is returned immediately while a
+// refresh is deduplicated and kicked off in the background. Otherwise it
+// blocks until the value has been loaded, sharing the load with any other
+// caller already waiting on the same key.
+//
+// hit reports whether the call returned an already-available value (fresh
+// or, under staleWhileRevalidate, stale) without itself waiting on a load.
+// It is false whenever this call blocked on startLoad, including joining a
+// load another caller already kicked off, so Container/Cache can count
+// Stats hits/misses by whether a caller actually got a value without
+// waiting, not merely by whether the item existed.
+func (i *item) Value() (value interface{}, err error, hit bool) {
 	i.mu.Lock()
-	if i.refreshing {
+	if i.loaded && time.Now().Before(i.expire) {
+		val, err := i.value, i.err
 		i.mu.Unlock()
-		return
+		return val, err, true
 	}
-	i.refreshing = true
-	go i.refresh()
+	if i.staleWhileRevalidate && i.loaded {
+		val, err := i.value, i.err
+		i.startLoad()
+		i.mu.Unlock()
+		return val, err, true
+	}
+	done := i.startLoad()
+	i.mu.Unlock()
+
+	<-done
+
+	i.mu.Lock()
+	val, err := i.value, i.err
 	i.mu.Unlock()
-	return
+	return val, err, false
 }
 
-func (i *item) refresh() {
-	// load data with fn
-	val, err := i.loadData()
+// startLoad ensures a load is in flight and returns the channel that closes
+// once it completes, joining an already-running load for this item rather
+// than starting a second one. Must be called with i.mu held.
+func (i *item) startLoad() chan struct{} {
+	if i.loading {
+		return i.done
+	}
+	i.loading = true
+	i.done = make(chan struct{})
+	go i.load(i.done)
+	return i.done
+}
+
+func (i *item) load(done chan struct{}) {
+	val, err := i.loadFn()
+
+	i.mu.Lock()
 	// don't cache response when underlying resouce exhausted
 	if err != ErrResourceExhausted {
 		i.value = val
 		i.err = err
+		i.loaded = true
+		i.expire = time.Now().Add(i.errorAwareTTL(err))
 	}
+	i.loading = false
+	i.mu.Unlock()
+
+	if i.onLoad != nil {
+		i.onLoad(err)
+	}
+	close(done)
+}
 
-	i.expire = time.Now().Add(i.ttl)
-	// reset refresh flag
-	i.refreshing = false
-	// set initialed flag
-	if !i.initialed {
-		i.initialed = true
-		close(i.initialCh)
+// errorAwareTTL returns how long the just-loaded result should be considered
+// fresh: the regular ttl for successful loads, or the configured ErrorTTL for
+// failed ones. A zero ErrorTTL means errors aren't cached at all, so the
+// result expires immediately. If ErrorTTL was never configured, errors are
+// cached for the same duration as successful results.
+func (i *item) errorAwareTTL(err error) time.Duration {
+	if err == nil || !i.hasErrTTL {
+		return i.ttl
 	}
+	return i.errTTL
 }
 
-// loadData is used to load data with fn and params
-func (i *item) loadData() (interface{}, error) {
-	f := reflect.ValueOf(i.fn)
+// reflectCall invokes fn, Container's reflect-based loader, with params via
+// reflect.Value. This is the per-miss allocation and dispatch cost
+// Cache[K, V] exists to avoid.
+func reflectCall(fn interface{}, params []interface{}) (interface{}, error) {
+	f := reflect.ValueOf(fn)
 	in := make([]reflect.Value, f.Type().NumIn())
-	for k, param := range i.params {
+	for k, param := range params {
 		in[k] = reflect.ValueOf(param)
 	}
 	res := f.Call(in)
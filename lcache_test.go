@@ -0,0 +1,117 @@
+package lcache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestContainerGetDedupsConcurrentLoads(t *testing.T) {
+	var calls int32
+	c := Must(New(func(key string) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return key, nil
+	}, time.Minute))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := c.Get("k")
+			if err != nil || val != "k" {
+				t.Errorf("Get() = %v, %v, want k, nil", val, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("fn called %d times, want exactly 1 (singleflight dedup)", got)
+	}
+}
+
+func TestContainerErrorTTL(t *testing.T) {
+	var calls int32
+	wantErr := errors.New("boom")
+	c := Must(NewWithOptions(func(key string) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "", wantErr
+	}, time.Minute, ErrorTTL(10*time.Millisecond)))
+
+	_, err := c.Get("k")
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Get() err = %v, want %v", err, wantErr)
+	}
+	if _, err := c.Get("k"); !errors.Is(err, wantErr) {
+		t.Fatalf("second Get() err = %v, want cached %v", err, wantErr)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("fn called %d times before ErrorTTL elapsed, want 1", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, err := c.Get("k"); !errors.Is(err, wantErr) {
+		t.Fatalf("Get() after ErrorTTL err = %v, want %v", err, wantErr)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("fn called %d times after ErrorTTL elapsed, want 2", got)
+	}
+}
+
+func TestContainerStaleWhileRevalidate(t *testing.T) {
+	var calls int32
+	c := Must(NewWithOptions(func(key string) (int32, error) {
+		n := atomic.AddInt32(&calls, 1)
+		return n, nil
+	}, 10*time.Millisecond, StaleWhileRevalidate()))
+
+	first, err := c.Get("k")
+	if err != nil || first != int32(1) {
+		t.Fatalf("first Get() = %v, %v, want 1, nil", first, err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	stale, err := c.Get("k")
+	if err != nil || stale != int32(1) {
+		t.Fatalf("Get() after expiry = %v, %v, want stale value 1, nil", stale, err)
+	}
+
+	for i := 0; i < 50 && atomic.LoadInt32(&calls) < 2; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("background refresh did not run, fn called %d times, want 2", got)
+	}
+
+	fresh, err := c.Get("k")
+	if err != nil || fresh != int32(2) {
+		t.Fatalf("Get() after refresh = %v, %v, want 2, nil", fresh, err)
+	}
+}
+
+func TestContainerResourceExhaustedNotCached(t *testing.T) {
+	var calls int32
+	c := Must(New(func(key string) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "", ErrResourceExhausted
+	}, time.Minute))
+
+	// A result of ErrResourceExhausted is never committed to the item, so
+	// it can never satisfy the fast path in Value() and every Get re-runs
+	// fn instead of serving a cached exhausted result.
+	for i := 0; i < 3; i++ {
+		c.Get("k")
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("fn called %d times, want 3 since ErrResourceExhausted results aren't cached", got)
+	}
+
+	if _, ok := c.Peek("k"); ok {
+		t.Fatalf("Peek() ok = true, want false since no value was ever committed")
+	}
+}
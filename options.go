@@ -0,0 +1,82 @@
+package lcache
+
+import "time"
+
+// containerOptions holds the optional behavior configured through Option
+// values passed to NewWithOptions.
+type containerOptions struct {
+	errTTL               time.Duration
+	hasErrTTL            bool
+	staleWhileRevalidate bool
+	policy               EvictionPolicy
+	keyFunc              KeyFunc
+	cleanupInterval      time.Duration
+	onEvict              func(params []interface{}, value interface{})
+}
+
+// Option configures optional behavior of a Container constructed with
+// NewWithOptions.
+type Option func(*containerOptions)
+
+// ErrorTTL sets how long an error returned by fn is cached before the next
+// Get triggers a fresh load. Passing zero disables error caching entirely,
+// so every Get after an error re-invokes fn. If ErrorTTL is not supplied,
+// errors are cached for the same duration as successful results.
+func ErrorTTL(d time.Duration) Option {
+	return func(o *containerOptions) {
+		o.errTTL = d
+		o.hasErrTTL = true
+	}
+}
+
+// StaleWhileRevalidate makes Get return the previous value immediately once
+// it has expired, while a refresh runs in the background, instead of
+// blocking the caller until the new value is loaded. The first load for a
+// key is never stale and always blocks.
+func StaleWhileRevalidate() Option {
+	return func(o *containerOptions) {
+		o.staleWhileRevalidate = true
+	}
+}
+
+// Policy selects the EvictionPolicy a Container uses, such as one built with
+// NewLRUPolicy, NewLRCPolicy, New2QPolicy, NewARCPolicy, or NewSIEVEPolicy.
+// The policy owns its own capacity, so when this option is supplied
+// DefaultCapacity is ignored in favor of the policy's.
+func Policy(p EvictionPolicy) Option {
+	return func(o *containerOptions) {
+		o.policy = p
+	}
+}
+
+// WithKeyFunc selects the KeyFunc a Container uses to derive a policy key
+// from a call's params, such as DefaultKeyFunc (the default), GobKeyFunc,
+// JSONKeyFunc, or FNVKeyFunc. It has no effect on fn taking exactly one
+// comparable, non-interface argument, since Container keys those directly
+// on the argument without calling KeyFunc at all.
+func WithKeyFunc(fn KeyFunc) Option {
+	return func(o *containerOptions) {
+		o.keyFunc = fn
+	}
+}
+
+// CleanupInterval starts a background janitor goroutine that sweeps for
+// expired entries every d via DeleteExpired, instead of relying solely on
+// the lazy expiration check inside Get. Stop the janitor with
+// Container.Close.
+func CleanupInterval(d time.Duration) Option {
+	return func(o *containerOptions) {
+		o.cleanupInterval = d
+	}
+}
+
+// OnEvict registers a callback invoked whenever an entry leaves the
+// container — through capacity eviction, an explicit Remove or Purge, or a
+// janitor sweep finding it expired — with the params it was keyed on and
+// its most recently loaded value. It is never called while holding the
+// container's internal lock.
+func OnEvict(fn func(params []interface{}, value interface{})) Option {
+	return func(o *containerOptions) {
+		o.onEvict = fn
+	}
+}
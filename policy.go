@@ -0,0 +1,50 @@
+package lcache
+
+// EvictionPolicy decides which entries a Container keeps and which it evicts once it
+// is over capacity. Container delegates all eviction bookkeeping to the
+// configured Policy, selected via NewWithOptions(Policy(...)); New and
+// NewWithSize use an LRU policy of the requested capacity by default.
+//
+// A key is whatever Container derives a call's params down to: normally the
+// string a KeyFunc returns, but the bare param itself when Container's
+// single-comparable-argument fast path applies. Either way it must be
+// comparable, since policies hold it in plain Go maps.
+type EvictionPolicy interface {
+	// Add inserts itm under key. If the policy is now over capacity, it
+	// evicts its chosen victim and returns it with evicted=true. Add is
+	// only called for keys the policy doesn't currently hold a live entry
+	// for (Container calls Get first); a policy may still recognize key
+	// from its own ghost/ history state and react accordingly.
+	Add(key interface{}, itm *item) (evictedKey interface{}, evictedItem *item, evicted bool)
+	// Get returns the item stored for key, recording the access (e.g.
+	// moving it to the front of an LRU list), or ok=false if key is absent.
+	Get(key interface{}) (itm *item, ok bool)
+	// Peek returns the item stored for key without recording an access, or
+	// ok=false if key is absent.
+	Peek(key interface{}) (itm *item, ok bool)
+	// Remove deletes key from the policy, reporting whether it was present.
+	Remove(key interface{}) bool
+	// Evict removes and returns the policy's next victim, or ok=false if
+	// the policy holds no entries.
+	Evict() (key interface{}, itm *item, ok bool)
+	// Len returns the number of live entries currently tracked.
+	Len() int
+	// Snapshot returns a copy of all currently tracked key -> item pairs,
+	// without affecting any ordering or access-recency state. Used by the
+	// janitor to find expired entries to sweep.
+	Snapshot() map[interface{}]*item
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
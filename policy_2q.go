@@ -0,0 +1,217 @@
+package lcache
+
+import "container/list"
+
+const (
+	// Default2QRecentRatio is the default fraction of total capacity
+	// reserved for the "recent" (seen-once) queue.
+	Default2QRecentRatio = 0.25
+	// Default2QGhostEntries is the default fraction of total capacity used
+	// to size the recent-eviction ghost list.
+	Default2QGhostEntries = 0.5
+)
+
+// twoQueuePolicy implements the 2Q eviction policy: entries seen once land
+// in a small "recent" queue; a second access promotes them into a larger LRU
+// "frequent" queue. A ghost list remembers keys recently evicted from
+// recent, so an entry that's added again shortly after eviction is promoted
+// straight into frequent instead of restarting in recent.
+type twoQueuePolicy struct {
+	size       int
+	recentSize int
+	ghostSize  int
+
+	recent      *list.List
+	recentItems map[interface{}]*list.Element
+
+	frequent      *list.List
+	frequentItems map[interface{}]*list.Element
+
+	recentEvict      *list.List
+	recentEvictItems map[interface{}]*list.Element
+}
+
+type twoQueueEntry struct {
+	key interface{}
+	itm *item // nil for ghost entries in recentEvict
+}
+
+// New2QPolicy constructs a 2Q Policy of the given total capacity, using the
+// default recent-queue and ghost-list ratios.
+func New2QPolicy(capacity int) EvictionPolicy {
+	return New2QPolicyParams(capacity, Default2QRecentRatio, Default2QGhostEntries)
+}
+
+// New2QPolicyParams constructs a 2Q Policy of the given total capacity, with
+// custom recent-queue and ghost-list ratios of that capacity.
+func New2QPolicyParams(capacity int, recentRatio, ghostRatio float64) EvictionPolicy {
+	return &twoQueuePolicy{
+		size:             capacity,
+		recentSize:       int(float64(capacity) * recentRatio),
+		ghostSize:        int(float64(capacity) * ghostRatio),
+		recent:           list.New(),
+		recentItems:      make(map[interface{}]*list.Element),
+		frequent:         list.New(),
+		frequentItems:    make(map[interface{}]*list.Element),
+		recentEvict:      list.New(),
+		recentEvictItems: make(map[interface{}]*list.Element),
+	}
+}
+
+func (p *twoQueuePolicy) Get(key interface{}) (*item, bool) {
+	if ent, ok := p.frequentItems[key]; ok {
+		p.frequent.MoveToFront(ent)
+		return ent.Value.(*twoQueueEntry).itm, true
+	}
+	if ent, ok := p.recentItems[key]; ok {
+		e := ent.Value.(*twoQueueEntry)
+		p.recent.Remove(ent)
+		delete(p.recentItems, key)
+		p.pushFrequent(key, e.itm)
+		return e.itm, true
+	}
+	return nil, false
+}
+
+// Peek returns the item for key without promoting it between the recent and
+// frequent queues.
+func (p *twoQueuePolicy) Peek(key interface{}) (*item, bool) {
+	if ent, ok := p.frequentItems[key]; ok {
+		return ent.Value.(*twoQueueEntry).itm, true
+	}
+	if ent, ok := p.recentItems[key]; ok {
+		return ent.Value.(*twoQueueEntry).itm, true
+	}
+	return nil, false
+}
+
+func (p *twoQueuePolicy) Add(key interface{}, itm *item) (interface{}, *item, bool) {
+	if ent, ok := p.frequentItems[key]; ok {
+		ent.Value.(*twoQueueEntry).itm = itm
+		p.frequent.MoveToFront(ent)
+		return nil, nil, false
+	}
+	if ent, ok := p.recentItems[key]; ok {
+		ent.Value.(*twoQueueEntry).itm = itm
+		p.recent.Remove(ent)
+		delete(p.recentItems, key)
+		p.pushFrequent(key, itm)
+		return nil, nil, false
+	}
+	if _, ok := p.recentEvictItems[key]; ok {
+		// ghost hit: this key was recently evicted from recent, so promote
+		// it straight into frequent instead of restarting in recent.
+		p.removeGhost(key)
+		evictedKey, evictedItem, evicted := p.ensureSpace()
+		p.pushFrequent(key, itm)
+		return evictedKey, evictedItem, evicted
+	}
+
+	evictedKey, evictedItem, evicted := p.ensureSpace()
+	ent := p.recent.PushFront(&twoQueueEntry{key: key, itm: itm})
+	p.recentItems[key] = ent
+	return evictedKey, evictedItem, evicted
+}
+
+// ensureSpace evicts one entry if the queues are at capacity, preferring to
+// push the recent queue's tail into the ghost list once recent has reached
+// its quota, and otherwise evicting from the tail of frequent.
+func (p *twoQueuePolicy) ensureSpace() (interface{}, *item, bool) {
+	if p.recent.Len()+p.frequent.Len() < p.size {
+		return nil, nil, false
+	}
+	if p.recent.Len() > 0 && p.recent.Len() >= maxInt(1, p.recentSize) {
+		return p.evictRecentToGhost()
+	}
+	return p.evictFrequent()
+}
+
+func (p *twoQueuePolicy) evictRecentToGhost() (interface{}, *item, bool) {
+	ent := p.recent.Back()
+	if ent == nil {
+		return p.evictFrequent()
+	}
+	p.recent.Remove(ent)
+	e := ent.Value.(*twoQueueEntry)
+	delete(p.recentItems, e.key)
+
+	ghost := p.recentEvict.PushFront(&twoQueueEntry{key: e.key})
+	p.recentEvictItems[e.key] = ghost
+	for p.recentEvict.Len() > maxInt(1, p.ghostSize) {
+		p.evictGhost()
+	}
+	return e.key, e.itm, true
+}
+
+func (p *twoQueuePolicy) evictFrequent() (interface{}, *item, bool) {
+	ent := p.frequent.Back()
+	if ent == nil {
+		return nil, nil, false
+	}
+	p.frequent.Remove(ent)
+	e := ent.Value.(*twoQueueEntry)
+	delete(p.frequentItems, e.key)
+	return e.key, e.itm, true
+}
+
+func (p *twoQueuePolicy) evictGhost() {
+	ent := p.recentEvict.Back()
+	if ent == nil {
+		return
+	}
+	p.recentEvict.Remove(ent)
+	delete(p.recentEvictItems, ent.Value.(*twoQueueEntry).key)
+}
+
+func (p *twoQueuePolicy) removeGhost(key interface{}) {
+	if ent, ok := p.recentEvictItems[key]; ok {
+		p.recentEvict.Remove(ent)
+		delete(p.recentEvictItems, key)
+	}
+}
+
+func (p *twoQueuePolicy) pushFrequent(key interface{}, itm *item) {
+	ent := p.frequent.PushFront(&twoQueueEntry{key: key, itm: itm})
+	p.frequentItems[key] = ent
+}
+
+func (p *twoQueuePolicy) Remove(key interface{}) bool {
+	if ent, ok := p.frequentItems[key]; ok {
+		p.frequent.Remove(ent)
+		delete(p.frequentItems, key)
+		return true
+	}
+	if ent, ok := p.recentItems[key]; ok {
+		p.recent.Remove(ent)
+		delete(p.recentItems, key)
+		return true
+	}
+	if ent, ok := p.recentEvictItems[key]; ok {
+		p.recentEvict.Remove(ent)
+		delete(p.recentEvictItems, key)
+		return true
+	}
+	return false
+}
+
+func (p *twoQueuePolicy) Evict() (interface{}, *item, bool) {
+	if p.recent.Len() > 0 {
+		return p.evictRecentToGhost()
+	}
+	return p.evictFrequent()
+}
+
+func (p *twoQueuePolicy) Len() int {
+	return p.recent.Len() + p.frequent.Len()
+}
+
+func (p *twoQueuePolicy) Snapshot() map[interface{}]*item {
+	out := make(map[interface{}]*item, len(p.recentItems)+len(p.frequentItems))
+	for k, ent := range p.recentItems {
+		out[k] = ent.Value.(*twoQueueEntry).itm
+	}
+	for k, ent := range p.frequentItems {
+		out[k] = ent.Value.(*twoQueueEntry).itm
+	}
+	return out
+}
@@ -0,0 +1,244 @@
+package lcache
+
+import "container/list"
+
+// arcPolicy implements Adaptive Replacement Cache: it maintains a recency
+// list (t1) and a frequency list (t2) of live entries, each shadowed by a
+// ghost list (b1, b2) of recently evicted keys. The adaptive target p shifts
+// the capacity split between t1 and t2 based on which ghost list is hitting
+// more, letting ARC self-tune between recency- and frequency-biased
+// workloads without any external configuration.
+type arcPolicy struct {
+	capacity int
+	p        int // target size of t1
+
+	t1, t2, b1, b2                     *list.List
+	t1Items, t2Items, b1Items, b2Items map[interface{}]*list.Element
+}
+
+type arcEntry struct {
+	key interface{}
+	itm *item // nil for ghost entries in b1/b2
+}
+
+// NewARCPolicy constructs an Adaptive Replacement Cache Policy of the given
+// capacity.
+func NewARCPolicy(capacity int) EvictionPolicy {
+	return &arcPolicy{
+		capacity: capacity,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		t1Items:  make(map[interface{}]*list.Element),
+		t2Items:  make(map[interface{}]*list.Element),
+		b1Items:  make(map[interface{}]*list.Element),
+		b2Items:  make(map[interface{}]*list.Element),
+	}
+}
+
+func (p *arcPolicy) Get(key interface{}) (*item, bool) {
+	if ent, ok := p.t1Items[key]; ok {
+		e := ent.Value.(*arcEntry)
+		p.t1.Remove(ent)
+		delete(p.t1Items, key)
+		p.pushT2(key, e.itm)
+		return e.itm, true
+	}
+	if ent, ok := p.t2Items[key]; ok {
+		p.t2.MoveToFront(ent)
+		return ent.Value.(*arcEntry).itm, true
+	}
+	return nil, false
+}
+
+// Peek returns the item for key without promoting it between t1 and t2.
+func (p *arcPolicy) Peek(key interface{}) (*item, bool) {
+	if ent, ok := p.t1Items[key]; ok {
+		return ent.Value.(*arcEntry).itm, true
+	}
+	if ent, ok := p.t2Items[key]; ok {
+		return ent.Value.(*arcEntry).itm, true
+	}
+	return nil, false
+}
+
+func (p *arcPolicy) Add(key interface{}, itm *item) (interface{}, *item, bool) {
+	if ent, ok := p.t1Items[key]; ok {
+		ent.Value.(*arcEntry).itm = itm
+		p.t1.Remove(ent)
+		delete(p.t1Items, key)
+		p.pushT2(key, itm)
+		return nil, nil, false
+	}
+	if ent, ok := p.t2Items[key]; ok {
+		ent.Value.(*arcEntry).itm = itm
+		p.t2.MoveToFront(ent)
+		return nil, nil, false
+	}
+
+	var evictedKey interface{}
+	var evictedItem *item
+	var evicted bool
+	full := p.t1.Len()+p.t2.Len() >= p.capacity
+
+	if _, ok := p.b1Items[key]; ok {
+		ratio := maxInt(1, p.b2.Len()/maxInt(1, p.b1.Len()))
+		p.p = minInt(p.capacity, p.p+ratio)
+		if full {
+			evictedKey, evictedItem, evicted = p.replace(false)
+		}
+		p.removeGhost(p.b1, p.b1Items, key)
+		p.pushT2(key, itm)
+		return evictedKey, evictedItem, evicted
+	}
+
+	if _, ok := p.b2Items[key]; ok {
+		ratio := maxInt(1, p.b1.Len()/maxInt(1, p.b2.Len()))
+		p.p = maxInt(0, p.p-ratio)
+		if full {
+			evictedKey, evictedItem, evicted = p.replace(true)
+		}
+		p.removeGhost(p.b2, p.b2Items, key)
+		p.pushT2(key, itm)
+		return evictedKey, evictedItem, evicted
+	}
+
+	t1Len, b1Len := p.t1.Len(), p.b1.Len()
+	switch {
+	case t1Len+b1Len == p.capacity:
+		if t1Len < p.capacity {
+			p.evictGhost(p.b1, p.b1Items)
+			if full {
+				evictedKey, evictedItem, evicted = p.replace(false)
+			}
+		} else {
+			ent := p.t1.Back()
+			e := ent.Value.(*arcEntry)
+			p.t1.Remove(ent)
+			delete(p.t1Items, e.key)
+			evictedKey, evictedItem, evicted = e.key, e.itm, true
+		}
+	case t1Len+p.t2.Len()+b1Len+p.b2.Len() >= p.capacity:
+		if t1Len+p.t2.Len()+b1Len+p.b2.Len() == 2*p.capacity {
+			p.evictGhost(p.b2, p.b2Items)
+		}
+		if full {
+			evictedKey, evictedItem, evicted = p.replace(false)
+		}
+	}
+	p.pushT1(key, itm)
+	return evictedKey, evictedItem, evicted
+}
+
+// replace evicts the tail of t1 into b1 or the tail of t2 into b2, per the
+// standard ARC replacement rule; inB2 reports whether the key that triggered
+// replacement was a b2 ghost hit.
+func (p *arcPolicy) replace(inB2 bool) (interface{}, *item, bool) {
+	t1Len := p.t1.Len()
+	if t1Len >= 1 && ((inB2 && t1Len == p.p) || t1Len > p.p) {
+		ent := p.t1.Back()
+		if ent == nil {
+			return nil, nil, false
+		}
+		e := ent.Value.(*arcEntry)
+		p.t1.Remove(ent)
+		delete(p.t1Items, e.key)
+		ghost := p.b1.PushFront(&arcEntry{key: e.key})
+		p.b1Items[e.key] = ghost
+		return e.key, e.itm, true
+	}
+	ent := p.t2.Back()
+	if ent == nil {
+		return nil, nil, false
+	}
+	e := ent.Value.(*arcEntry)
+	p.t2.Remove(ent)
+	delete(p.t2Items, e.key)
+	ghost := p.b2.PushFront(&arcEntry{key: e.key})
+	p.b2Items[e.key] = ghost
+	return e.key, e.itm, true
+}
+
+func (p *arcPolicy) evictGhost(l *list.List, m map[interface{}]*list.Element) {
+	ent := l.Back()
+	if ent == nil {
+		return
+	}
+	l.Remove(ent)
+	delete(m, ent.Value.(*arcEntry).key)
+}
+
+func (p *arcPolicy) removeGhost(l *list.List, m map[interface{}]*list.Element, key interface{}) {
+	if ent, ok := m[key]; ok {
+		l.Remove(ent)
+		delete(m, key)
+	}
+}
+
+func (p *arcPolicy) pushT1(key interface{}, itm *item) {
+	ent := p.t1.PushFront(&arcEntry{key: key, itm: itm})
+	p.t1Items[key] = ent
+}
+
+func (p *arcPolicy) pushT2(key interface{}, itm *item) {
+	ent := p.t2.PushFront(&arcEntry{key: key, itm: itm})
+	p.t2Items[key] = ent
+}
+
+func (p *arcPolicy) Remove(key interface{}) bool {
+	if ent, ok := p.t1Items[key]; ok {
+		p.t1.Remove(ent)
+		delete(p.t1Items, key)
+		return true
+	}
+	if ent, ok := p.t2Items[key]; ok {
+		p.t2.Remove(ent)
+		delete(p.t2Items, key)
+		return true
+	}
+	if ent, ok := p.b1Items[key]; ok {
+		p.b1.Remove(ent)
+		delete(p.b1Items, key)
+		return true
+	}
+	if ent, ok := p.b2Items[key]; ok {
+		p.b2.Remove(ent)
+		delete(p.b2Items, key)
+		return true
+	}
+	return false
+}
+
+func (p *arcPolicy) Evict() (interface{}, *item, bool) {
+	if p.t1.Len() > 0 {
+		ent := p.t1.Back()
+		e := ent.Value.(*arcEntry)
+		p.t1.Remove(ent)
+		delete(p.t1Items, e.key)
+		return e.key, e.itm, true
+	}
+	if p.t2.Len() > 0 {
+		ent := p.t2.Back()
+		e := ent.Value.(*arcEntry)
+		p.t2.Remove(ent)
+		delete(p.t2Items, e.key)
+		return e.key, e.itm, true
+	}
+	return nil, nil, false
+}
+
+func (p *arcPolicy) Len() int {
+	return p.t1.Len() + p.t2.Len()
+}
+
+func (p *arcPolicy) Snapshot() map[interface{}]*item {
+	out := make(map[interface{}]*item, len(p.t1Items)+len(p.t2Items))
+	for k, ent := range p.t1Items {
+		out[k] = ent.Value.(*arcEntry).itm
+	}
+	for k, ent := range p.t2Items {
+		out[k] = ent.Value.(*arcEntry).itm
+	}
+	return out
+}
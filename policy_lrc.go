@@ -0,0 +1,92 @@
+package lcache
+
+import "container/list"
+
+// lrcPolicy implements least-recently-created eviction: entries are evicted
+// in the order they were inserted, and a Get hit never reorders the queue
+// (unlike LRU). This suits workloads where the age of an entry, not how
+// often it's read, should decide who gets evicted.
+type lrcPolicy struct {
+	capacity int
+	items    map[interface{}]*list.Element
+	order    *list.List
+}
+
+type lrcEntry struct {
+	key interface{}
+	itm *item
+}
+
+// NewLRCPolicy constructs a Policy that evicts the least-recently-created
+// entry once more than capacity entries are held.
+func NewLRCPolicy(capacity int) EvictionPolicy {
+	return &lrcPolicy{
+		capacity: capacity,
+		items:    make(map[interface{}]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (p *lrcPolicy) Add(key interface{}, itm *item) (interface{}, *item, bool) {
+	if ent, ok := p.items[key]; ok {
+		ent.Value.(*lrcEntry).itm = itm
+		return nil, nil, false
+	}
+	ent := p.order.PushBack(&lrcEntry{key: key, itm: itm})
+	p.items[key] = ent
+	if p.order.Len() > p.capacity {
+		return p.evict()
+	}
+	return nil, nil, false
+}
+
+func (p *lrcPolicy) Get(key interface{}) (*item, bool) {
+	ent, ok := p.items[key]
+	if !ok {
+		return nil, false
+	}
+	return ent.Value.(*lrcEntry).itm, true
+}
+
+// Peek is equivalent to Get: lrcPolicy never reorders on access, so there is
+// nothing extra to skip.
+func (p *lrcPolicy) Peek(key interface{}) (*item, bool) {
+	return p.Get(key)
+}
+
+func (p *lrcPolicy) Remove(key interface{}) bool {
+	ent, ok := p.items[key]
+	if !ok {
+		return false
+	}
+	p.order.Remove(ent)
+	delete(p.items, key)
+	return true
+}
+
+func (p *lrcPolicy) Evict() (interface{}, *item, bool) {
+	return p.evict()
+}
+
+func (p *lrcPolicy) evict() (interface{}, *item, bool) {
+	ent := p.order.Front()
+	if ent == nil {
+		return nil, nil, false
+	}
+	p.order.Remove(ent)
+	e := ent.Value.(*lrcEntry)
+	delete(p.items, e.key)
+	return e.key, e.itm, true
+}
+
+func (p *lrcPolicy) Len() int {
+	return p.order.Len()
+}
+
+func (p *lrcPolicy) Snapshot() map[interface{}]*item {
+	out := make(map[interface{}]*item, len(p.items))
+	for k, ent := range p.items {
+		out[k] = ent.Value.(*lrcEntry).itm
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package lcache
+
+import "container/list"
+
+// lruPolicy is the classic least-recently-used eviction policy: every Get
+// hit moves the accessed entry to the front of the list, and Add evicts from
+// the back once more than capacity entries are held. It's the Policy used by
+// New and NewWithSize.
+type lruPolicy struct {
+	capacity  int
+	items     map[interface{}]*list.Element
+	evictList *list.List
+}
+
+type lruEntry struct {
+	key interface{}
+	itm *item
+}
+
+// NewLRUPolicy constructs a Policy that evicts the least-recently-used entry
+// once more than capacity entries are held.
+func NewLRUPolicy(capacity int) EvictionPolicy {
+	return &lruPolicy{
+		capacity:  capacity,
+		items:     make(map[interface{}]*list.Element),
+		evictList: list.New(),
+	}
+}
+
+func (p *lruPolicy) Add(key interface{}, itm *item) (interface{}, *item, bool) {
+	if ent, ok := p.items[key]; ok {
+		ent.Value.(*lruEntry).itm = itm
+		p.evictList.MoveToFront(ent)
+		return nil, nil, false
+	}
+	ent := p.evictList.PushFront(&lruEntry{key: key, itm: itm})
+	p.items[key] = ent
+	if p.evictList.Len() > p.capacity {
+		return p.evict()
+	}
+	return nil, nil, false
+}
+
+func (p *lruPolicy) Get(key interface{}) (*item, bool) {
+	ent, ok := p.items[key]
+	if !ok {
+		return nil, false
+	}
+	p.evictList.MoveToFront(ent)
+	return ent.Value.(*lruEntry).itm, true
+}
+
+func (p *lruPolicy) Peek(key interface{}) (*item, bool) {
+	ent, ok := p.items[key]
+	if !ok {
+		return nil, false
+	}
+	return ent.Value.(*lruEntry).itm, true
+}
+
+func (p *lruPolicy) Remove(key interface{}) bool {
+	ent, ok := p.items[key]
+	if !ok {
+		return false
+	}
+	p.evictList.Remove(ent)
+	delete(p.items, key)
+	return true
+}
+
+func (p *lruPolicy) Evict() (interface{}, *item, bool) {
+	return p.evict()
+}
+
+func (p *lruPolicy) evict() (interface{}, *item, bool) {
+	ent := p.evictList.Back()
+	if ent == nil {
+		return nil, nil, false
+	}
+	p.evictList.Remove(ent)
+	e := ent.Value.(*lruEntry)
+	delete(p.items, e.key)
+	return e.key, e.itm, true
+}
+
+func (p *lruPolicy) Len() int {
+	return p.evictList.Len()
+}
+
+func (p *lruPolicy) Snapshot() map[interface{}]*item {
+	out := make(map[interface{}]*item, len(p.items))
+	for k, ent := range p.items {
+		out[k] = ent.Value.(*lruEntry).itm
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+package lcache
+
+import "container/list"
+
+// sievePolicy implements the SIEVE eviction algorithm: entries sit in a
+// single FIFO queue, each carrying a "visited" bit set on every hit. A hand
+// pointer sweeps backward from wherever it last stopped, clearing the
+// visited bit of everything it passes and evicting the first unvisited
+// entry it finds, so popular entries survive without ever being moved
+// within the list.
+type sievePolicy struct {
+	capacity int
+	items    map[interface{}]*list.Element
+	queue    *list.List
+	hand     *list.Element
+}
+
+type sieveEntry struct {
+	key     interface{}
+	itm     *item
+	visited bool
+}
+
+// NewSIEVEPolicy constructs a Policy implementing the SIEVE eviction
+// algorithm with the given capacity.
+func NewSIEVEPolicy(capacity int) EvictionPolicy {
+	return &sievePolicy{
+		capacity: capacity,
+		items:    make(map[interface{}]*list.Element),
+		queue:    list.New(),
+	}
+}
+
+func (p *sievePolicy) Get(key interface{}) (*item, bool) {
+	ent, ok := p.items[key]
+	if !ok {
+		return nil, false
+	}
+	ent.Value.(*sieveEntry).visited = true
+	return ent.Value.(*sieveEntry).itm, true
+}
+
+// Peek returns the item for key without setting its visited bit.
+func (p *sievePolicy) Peek(key interface{}) (*item, bool) {
+	ent, ok := p.items[key]
+	if !ok {
+		return nil, false
+	}
+	return ent.Value.(*sieveEntry).itm, true
+}
+
+func (p *sievePolicy) Add(key interface{}, itm *item) (interface{}, *item, bool) {
+	if ent, ok := p.items[key]; ok {
+		e := ent.Value.(*sieveEntry)
+		e.itm = itm
+		e.visited = true
+		return nil, nil, false
+	}
+	var evictedKey interface{}
+	var evictedItem *item
+	var evicted bool
+	if p.queue.Len() >= p.capacity {
+		evictedKey, evictedItem, evicted = p.evict()
+	}
+	// new entries enter at the head; the hand sweeps in from the tail.
+	ent := p.queue.PushFront(&sieveEntry{key: key, itm: itm})
+	p.items[key] = ent
+	return evictedKey, evictedItem, evicted
+}
+
+func (p *sievePolicy) Remove(key interface{}) bool {
+	ent, ok := p.items[key]
+	if !ok {
+		return false
+	}
+	if p.hand == ent {
+		p.hand = p.stepBack(ent)
+	}
+	p.queue.Remove(ent)
+	delete(p.items, key)
+	return true
+}
+
+func (p *sievePolicy) Evict() (interface{}, *item, bool) {
+	return p.evict()
+}
+
+func (p *sievePolicy) evict() (interface{}, *item, bool) {
+	if p.queue.Len() == 0 {
+		return nil, nil, false
+	}
+	hand := p.hand
+	if hand == nil {
+		hand = p.queue.Back()
+	}
+	for {
+		e := hand.Value.(*sieveEntry)
+		if !e.visited {
+			break
+		}
+		e.visited = false
+		// stepBack reports nil when hand is the only element; stay on it,
+		// since its bit is now clear and the next pass evicts it.
+		if next := p.stepBack(hand); next != nil {
+			hand = next
+		}
+	}
+
+	p.hand = p.stepBack(hand)
+	e := hand.Value.(*sieveEntry)
+	p.queue.Remove(hand)
+	delete(p.items, e.key)
+	return e.key, e.itm, true
+}
+
+// stepBack returns the element the hand should land on after passing e:
+// the previous element, wrapping around to the tail once the hand walks
+// past the front. Reports nil once e is the only element left, since e is
+// about to be removed.
+func (p *sievePolicy) stepBack(e *list.Element) *list.Element {
+	next := e.Prev()
+	if next == nil {
+		next = p.queue.Back()
+	}
+	if next == e {
+		return nil
+	}
+	return next
+}
+
+func (p *sievePolicy) Len() int {
+	return p.queue.Len()
+}
+
+func (p *sievePolicy) Snapshot() map[interface{}]*item {
+	out := make(map[interface{}]*item, len(p.items))
+	for k, ent := range p.items {
+		out[k] = ent.Value.(*sieveEntry).itm
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package lcache
+
+import "testing"
+
+func newTestItem(v string) *item {
+	return &item{value: v, loaded: true}
+}
+
+func TestPoliciesEvictOverCapacity(t *testing.T) {
+	policies := map[string]func(int) EvictionPolicy{
+		"LRU":   NewLRUPolicy,
+		"LRC":   NewLRCPolicy,
+		"2Q":    New2QPolicy,
+		"ARC":   NewARCPolicy,
+		"SIEVE": NewSIEVEPolicy,
+	}
+
+	for name, newPolicy := range policies {
+		t.Run(name, func(t *testing.T) {
+			p := newPolicy(2)
+
+			if _, _, evicted := p.Add("a", newTestItem("a")); evicted {
+				t.Fatalf("Add(a) evicted below capacity")
+			}
+			if _, _, evicted := p.Add("b", newTestItem("b")); evicted {
+				t.Fatalf("Add(b) evicted below capacity")
+			}
+			if got := p.Len(); got != 2 {
+				t.Fatalf("Len() = %d, want 2", got)
+			}
+
+			if _, ok := p.Get("a"); !ok {
+				t.Fatalf("Get(a) ok = false, want true")
+			}
+
+			_, evictedItem, evicted := p.Add("c", newTestItem("c"))
+			if !evicted {
+				t.Fatalf("Add(c) over capacity did not evict")
+			}
+			if evictedItem == nil {
+				t.Fatalf("Add(c) reported evicted but returned a nil item")
+			}
+			if got := p.Len(); got != 2 {
+				t.Fatalf("Len() after eviction = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestPoliciesRemoveAndPeek(t *testing.T) {
+	policies := map[string]func(int) EvictionPolicy{
+		"LRU":   NewLRUPolicy,
+		"LRC":   NewLRCPolicy,
+		"2Q":    New2QPolicy,
+		"ARC":   NewARCPolicy,
+		"SIEVE": NewSIEVEPolicy,
+	}
+
+	for name, newPolicy := range policies {
+		t.Run(name, func(t *testing.T) {
+			p := newPolicy(4)
+			p.Add("a", newTestItem("a"))
+
+			if _, ok := p.Peek("a"); !ok {
+				t.Fatalf("Peek(a) ok = false, want true")
+			}
+			if _, ok := p.Peek("missing"); ok {
+				t.Fatalf("Peek(missing) ok = true, want false")
+			}
+
+			if !p.Remove("a") {
+				t.Fatalf("Remove(a) = false, want true")
+			}
+			if p.Remove("a") {
+				t.Fatalf("second Remove(a) = true, want false")
+			}
+			if _, ok := p.Get("a"); ok {
+				t.Fatalf("Get(a) ok = true after Remove, want false")
+			}
+		})
+	}
+}
+
+func TestLRUPolicyEvictsLeastRecentlyUsed(t *testing.T) {
+	p := NewLRUPolicy(2)
+	p.Add("a", newTestItem("a"))
+	p.Add("b", newTestItem("b"))
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	p.Get("a")
+
+	evictedKey, _, evicted := p.Add("c", newTestItem("c"))
+	if !evicted || evictedKey != "b" {
+		t.Fatalf("Add(c) evicted = (%v, %v), want (b, true)", evictedKey, evicted)
+	}
+}
+
+func TestLRCPolicyEvictsOldestRegardlessOfAccess(t *testing.T) {
+	p := NewLRCPolicy(2)
+	p.Add("a", newTestItem("a"))
+	p.Add("b", newTestItem("b"))
+
+	// Unlike LRU, accessing "a" must not protect it from eviction: LRC
+	// orders strictly by insertion time.
+	p.Get("a")
+
+	evictedKey, _, evicted := p.Add("c", newTestItem("c"))
+	if !evicted || evictedKey != "a" {
+		t.Fatalf("Add(c) evicted = (%v, %v), want (a, true)", evictedKey, evicted)
+	}
+}
+
+func TestSIEVEPolicySnapshot(t *testing.T) {
+	p := NewSIEVEPolicy(4)
+	p.Add("a", newTestItem("a"))
+	p.Add("b", newTestItem("b"))
+
+	snap := p.Snapshot()
+	if len(snap) != 2 {
+		t.Fatalf("Snapshot() has %d entries, want 2", len(snap))
+	}
+	if itm, ok := snap["a"]; !ok || itm.value != "a" {
+		t.Fatalf("Snapshot()[a] = %v, %v, want a, true", itm, ok)
+	}
+}
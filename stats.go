@@ -0,0 +1,33 @@
+package lcache
+
+// Stats holds cumulative counters describing a Container's activity since
+// construction, as returned by Container.Stats.
+type Stats struct {
+	// Hits is the number of Get calls that returned an already-loaded value
+	// without waiting on a load, including stale values served under
+	// StaleWhileRevalidate.
+	Hits uint64
+	// Misses is the number of Get calls that waited on a load: the entry
+	// was absent, had expired, or was still loading for another caller.
+	Misses uint64
+	// Evictions is the number of entries removed from the container,
+	// whether by capacity eviction, Remove, Purge, DeleteExpired, or
+	// InvalidateFn.
+	Evictions uint64
+	// Loads is the number of times fn was invoked to produce a value.
+	Loads uint64
+	// LoadErrors is the number of Loads that returned a non-nil error.
+	LoadErrors uint64
+	// Size is the current number of entries held by the container.
+	Size int
+}
+
+// statsCounters holds the atomic counters backing Container.Stats. Size is
+// not tracked here since it is derived from the policy at snapshot time.
+type statsCounters struct {
+	hits       uint64
+	misses     uint64
+	evictions  uint64
+	loads      uint64
+	loadErrors uint64
+}